@@ -0,0 +1,12 @@
+// Package audio provides a small decoding front-end for feeding
+// non-native audio (WAV files, other sample rates, other sample
+// formats) into snowboy.Detector without hand-rolled byte<->int16
+// conversion at the call site.
+package audio
+
+// Source reads decoded PCM audio as signed 16-bit samples. Unlike
+// io.Reader, Read deals in samples rather than bytes, so adapters never
+// have to worry about an odd number of bytes splitting a sample.
+type Source interface {
+	Read(p []int16) (n int, err error)
+}
@@ -0,0 +1,64 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// Int32Source reads little-endian, signed 32-bit PCM samples from r and
+// scales them down to int16, the format SnowboyDetect expects.
+type Int32Source struct {
+	r io.Reader
+}
+
+// NewInt32Source wraps r, which must yield raw little-endian int32
+// samples (e.g. what some PortAudio devices emit natively).
+func NewInt32Source(r io.Reader) *Int32Source {
+	return &Int32Source{r: r}
+}
+
+func (s *Int32Source) Read(p []int16) (int, error) {
+	buf := make([]byte, len(p)*4)
+	n, err := io.ReadFull(s.r, buf)
+	samples := n / 4
+	for i := 0; i < samples; i++ {
+		v := int32(binary.LittleEndian.Uint32(buf[i*4:]))
+		p[i] = int16(v >> 16)
+	}
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return samples, err
+}
+
+// Float32Source reads little-endian float32 samples in [-1.0, 1.0] from
+// r and converts them to int16, the format SnowboyDetect expects.
+type Float32Source struct {
+	r io.Reader
+}
+
+// NewFloat32Source wraps r, which must yield raw little-endian float32
+// samples (e.g. what a PortAudio stream with []float32 buffers captures).
+func NewFloat32Source(r io.Reader) *Float32Source {
+	return &Float32Source{r: r}
+}
+
+func (s *Float32Source) Read(p []int16) (int, error) {
+	buf := make([]byte, len(p)*4)
+	n, err := io.ReadFull(s.r, buf)
+	samples := n / 4
+	for i := 0; i < samples; i++ {
+		f := math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+		if f > 1 {
+			f = 1
+		} else if f < -1 {
+			f = -1
+		}
+		p[i] = int16(f * math.MaxInt16)
+	}
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return samples, err
+}
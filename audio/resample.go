@@ -0,0 +1,106 @@
+package audio
+
+import "io"
+
+// Resampler linearly resamples a Source from one sample rate to
+// another, preserving channel interleaving. It's deliberately simple
+// (no anti-aliasing filter) since its job is to get mismatched mic/file
+// input close enough to a model's native rate, not studio-quality SRC.
+type Resampler struct {
+	src         Source
+	numChannels int
+	ratio       float64 // srcRate / dstRate
+
+	// pos is the fractional read position between buf's two frames.
+	pos  float64
+	buf  []int16 // two frames of source audio, for interpolation
+	have int     // number of valid frames currently in buf (0, 1 or 2)
+	eof  bool
+}
+
+// NewResampler wraps src, which produces audio at srcRate, and makes it
+// look like a Source at dstRate. numChannels must match src's
+// interleaving and is preserved on output.
+func NewResampler(src Source, numChannels, srcRate, dstRate int) *Resampler {
+	return &Resampler{
+		src:         src,
+		numChannels: numChannels,
+		ratio:       float64(srcRate) / float64(dstRate),
+		buf:         make([]int16, 2*numChannels),
+	}
+}
+
+// Read fills p with up to len(p)/numChannels resampled frames.
+func (r *Resampler) Read(p []int16) (int, error) {
+	nc := r.numChannels
+	frames := len(p) / nc
+
+	for f := 0; f < frames; f++ {
+		if err := r.ensureFrames(); err != nil {
+			return f * nc, err
+		}
+
+		frac := r.pos
+		for c := 0; c < nc; c++ {
+			a := float64(r.buf[c])
+			b := float64(r.buf[nc+c])
+			p[f*nc+c] = int16(a + (b-a)*frac)
+		}
+
+		r.pos += r.ratio
+		for r.pos >= 1 {
+			r.pos--
+			if err := r.advance(); err != nil {
+				return (f + 1) * nc, err
+			}
+		}
+	}
+
+	return frames * nc, nil
+}
+
+// ensureFrames makes sure buf holds two consecutive source frames to
+// interpolate between.
+func (r *Resampler) ensureFrames() error {
+	for r.have < 2 {
+		if err := r.advance(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// advance reads one more source frame into buf, shifting the older one
+// out.
+func (r *Resampler) advance() error {
+	if r.eof {
+		return io.EOF
+	}
+	nc := r.numChannels
+	copy(r.buf, r.buf[nc:])
+
+	if err := r.readFrame(r.buf[nc:]); err != nil {
+		r.eof = true
+		return err
+	}
+	if r.have < 2 {
+		r.have++
+	}
+	return nil
+}
+
+// readFrame fills frame (one sample per channel) from src, issuing
+// repeated Reads since Source.Read may return partial frames.
+func (r *Resampler) readFrame(frame []int16) error {
+	for n := 0; n < len(frame); {
+		read, err := r.src.Read(frame[n:])
+		n += read
+		if err != nil {
+			if n == len(frame) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
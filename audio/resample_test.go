@@ -0,0 +1,86 @@
+package audio
+
+import (
+	"io"
+	"testing"
+)
+
+// sliceSource is a Source over a fixed, in-memory sequence of samples,
+// for use in tests only.
+type sliceSource struct {
+	samples []int16
+	pos     int
+}
+
+func (s *sliceSource) Read(p []int16) (int, error) {
+	if s.pos >= len(s.samples) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.samples[s.pos:])
+	s.pos += n
+	return n, nil
+}
+
+func TestResamplerUpsampleInterpolates(t *testing.T) {
+	src := &sliceSource{samples: []int16{0, 10, 20, 30, 40}}
+	r := NewResampler(src, 1, 1, 2) // srcRate=1, dstRate=2 -> ratio 0.5
+
+	out := make([]int16, 4)
+	n, err := r.Read(out)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if n != 4 {
+		t.Fatalf("Read() = %d, want 4", n)
+	}
+
+	want := []int16{0, 5, 10, 15}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %d, want %d", i, out[i], v)
+		}
+	}
+}
+
+func TestResamplerDownsamplePicksEveryNth(t *testing.T) {
+	src := &sliceSource{samples: []int16{0, 10, 20, 30, 40, 50, 60, 70}}
+	r := NewResampler(src, 1, 2, 1) // srcRate=2, dstRate=1 -> ratio 2
+
+	out := make([]int16, 3)
+	n, err := r.Read(out)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("Read() = %d, want 3", n)
+	}
+
+	want := []int16{0, 20, 40}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %d, want %d", i, out[i], v)
+		}
+	}
+}
+
+func TestResamplerPreservesChannelInterleaving(t *testing.T) {
+	// Two channels, identity rate: L/R should pass through unchanged.
+	src := &sliceSource{samples: []int16{1, -1, 2, -2, 3, -3, 4, -4, 5, -5}}
+	r := NewResampler(src, 2, 1, 1)
+
+	out := make([]int16, 6)
+	n, err := r.Read(out)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if n != 6 {
+		t.Fatalf("Read() = %d samples, want 6 (3 frames)", n)
+	}
+
+	want := []int16{1, -1, 2, -2, 3, -3}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %d, want %d", i, out[i], v)
+		}
+	}
+}
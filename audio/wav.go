@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotWAV is returned when the input does not start with a RIFF/WAVE
+// header.
+var ErrNotWAV = errors.New("audio: not a WAV file")
+
+// WAVFormat describes the format chunk of a WAV file.
+type WAVFormat struct {
+	NumChannels   int
+	SampleRate    int
+	BitsPerSample int
+}
+
+// wavSource strips the RIFF/WAVE header from r and exposes the
+// remaining PCM data chunk as a Source of int16 samples. It only
+// supports 16-bit PCM data; NewWAVSource returns an error for any other
+// bit depth.
+type wavSource struct {
+	r      io.Reader
+	format WAVFormat
+}
+
+// NewWAVSource parses the RIFF/WAVE header from r, returning a Source
+// over its PCM data chunk along with the format the header declared.
+func NewWAVSource(r io.Reader) (Source, WAVFormat, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, WAVFormat{}, err
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, WAVFormat{}, ErrNotWAV
+	}
+
+	var format WAVFormat
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return nil, WAVFormat{}, err
+		}
+		id := string(header[0:4])
+		size := binary.LittleEndian.Uint32(header[4:8])
+
+		if id == "fmt " {
+			if size < 16 {
+				return nil, WAVFormat{}, fmt.Errorf("%w: fmt chunk too short (%d bytes)", ErrNotWAV, size)
+			}
+			body := make([]byte, size)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, WAVFormat{}, err
+			}
+			format.NumChannels = int(binary.LittleEndian.Uint16(body[2:4]))
+			format.SampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			format.BitsPerSample = int(binary.LittleEndian.Uint16(body[14:16]))
+			continue
+		}
+
+		if id == "data" {
+			if format.BitsPerSample != 16 {
+				return nil, WAVFormat{}, fmt.Errorf("audio: unsupported WAV bit depth %d, only 16-bit PCM is supported", format.BitsPerSample)
+			}
+			return &wavSource{r: io.LimitReader(r, int64(size))}, format, nil
+		}
+
+		// Unknown chunk (e.g. "LIST", "fact"): skip it and keep scanning.
+		if _, err := io.CopyN(io.Discard, r, int64(size)); err != nil {
+			return nil, WAVFormat{}, err
+		}
+	}
+}
+
+func (w *wavSource) Read(p []int16) (int, error) {
+	buf := make([]byte, len(p)*2)
+	n, err := io.ReadFull(w.r, buf)
+	samples := n / 2
+	for i := 0; i < samples; i++ {
+		p[i] = int16(binary.LittleEndian.Uint16(buf[i*2:]))
+	}
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return samples, err
+}
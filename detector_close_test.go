@@ -0,0 +1,51 @@
+package snowboy
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewDetectorCreatesCloseChEagerly guards against the chunk0-3
+// regression where closeCh was created lazily inside initialize();
+// closeOnce could then fire (and silently skip closing) before
+// initialize() ever ran, permanently losing the close signal.
+func TestNewDetectorCreatesCloseChEagerly(t *testing.T) {
+	d := NewDetector("")
+	if d.closeCh == nil {
+		t.Fatal("NewDetector must create closeCh eagerly, before any initialize() call")
+	}
+}
+
+// TestCloseUnblocksPendingCloseChWaitBeforeInitialize exercises the
+// exact race from the chunk0-3 review: Close() called on a Detector
+// that has never had initialize() run. Because closeCh is created in
+// NewDetector rather than lazily, a goroutine already selecting on it
+// (as ReadAndDetectContext's read loop does) must unblock.
+func TestCloseUnblocksPendingCloseChWaitBeforeInitialize(t *testing.T) {
+	d := NewDetector("")
+
+	unblocked := make(chan struct{})
+	go func() {
+		<-d.closeCh
+		close(unblocked)
+	}()
+
+	if err := d.Close(); err == nil {
+		t.Fatal("Close() on a never-initialized Detector should return an error")
+	}
+
+	select {
+	case <-unblocked:
+	case <-time.After(time.Second):
+		t.Fatal("closeCh was not closed by Close(), pending waiter stayed blocked")
+	}
+}
+
+// TestCloseIsIdempotent confirms a second Close() call doesn't panic on
+// an already-closed closeCh (closeOnce must still guard the close
+// itself, even though it now runs under d.mu).
+func TestCloseIsIdempotent(t *testing.T) {
+	d := NewDetector("")
+	d.Close()
+	d.Close()
+}
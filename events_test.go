@@ -0,0 +1,67 @@
+package snowboy
+
+import "testing"
+
+func TestPublishQueuesEvents(t *testing.T) {
+	d := &Detector{EventBufferSize: 2}
+	events := d.Events()
+
+	d.publish(EventHotword, "a")
+	d.publish(EventSilence, "b")
+
+	for _, want := range []struct {
+		kind    EventKind
+		keyword string
+	}{
+		{EventHotword, "a"},
+		{EventSilence, "b"},
+	} {
+		select {
+		case ev := <-events:
+			if ev.Kind != want.kind || ev.Keyword != want.keyword {
+				t.Errorf("got %+v, want kind=%v keyword=%q", ev, want.kind, want.keyword)
+			}
+		default:
+			t.Fatal("expected a queued event")
+		}
+	}
+}
+
+func TestPublishDropOldest(t *testing.T) {
+	d := &Detector{EventBufferSize: 2, EventDropOldest: true}
+	events := d.Events()
+
+	d.publish(EventHotword, "a")
+	d.publish(EventHotword, "b")
+	d.publish(EventHotword, "c") // buffer is full; "a" should be dropped for "c"
+
+	var got []string
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-events:
+			got = append(got, ev.Keyword)
+		default:
+			t.Fatalf("expected 2 queued events, only got %d", i)
+		}
+	}
+
+	want := []string{"b", "c"}
+	for i, k := range want {
+		if got[i] != k {
+			t.Errorf("got[%d] = %q, want %q", i, got[i], k)
+		}
+	}
+
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected extra event: %+v", ev)
+	default:
+	}
+}
+
+func TestPublishWithoutListenerIsNoop(t *testing.T) {
+	d := &Detector{}
+	// Events() was never called, so eventCh is nil; publish must not
+	// panic or block.
+	d.publish(EventHotword, "a")
+}
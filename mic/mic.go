@@ -0,0 +1,104 @@
+// Package mic provides a PortAudio-backed io.Reader that streams live
+// microphone input in the little-endian 16-bit PCM format expected by
+// snowboy.Detector.
+package mic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// ErrClosed is returned by Read once the Source has been closed.
+var ErrClosed = errors.New("mic: source closed")
+
+// Source is an io.Reader backed by a PortAudio input stream. Audio
+// captured on the PortAudio callback goroutine is pushed into a
+// lock-free ring buffer so that Read (and, by extension,
+// Detector.ReadAndDetect) never blocks the audio thread.
+type Source struct {
+	stream *portaudio.Stream
+	ring   *ringBuffer
+	closed int32
+}
+
+// Open starts a PortAudio input stream matching sampleRate, numChannels
+// and bitsPerSample, as returned by Detector.AudioFormat(). Only 16-bit
+// samples are supported, matching what SnowboyDetect expects.
+//
+// The ring buffer is sized like the Python reference implementation:
+// numChannels * sampleRate * 5 samples, i.e. five seconds of audio.
+func Open(sampleRate, numChannels, bitsPerSample int) (*Source, error) {
+	if bitsPerSample != 16 {
+		return nil, fmt.Errorf("mic: unsupported bit depth %d, only 16-bit PCM is supported", bitsPerSample)
+	}
+
+	if err := portaudio.Initialize(); err != nil {
+		return nil, err
+	}
+
+	s := &Source{
+		ring: newRingBuffer(numChannels * sampleRate * 5),
+	}
+
+	stream, err := portaudio.OpenDefaultStream(numChannels, 0, float64(sampleRate), 0, s.callback)
+	if err != nil {
+		portaudio.Terminate()
+		return nil, err
+	}
+	s.stream = stream
+
+	if err := stream.Start(); err != nil {
+		stream.Close()
+		portaudio.Terminate()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// callback runs on the PortAudio audio thread. It must never block, so a
+// full ring buffer drops the incoming frame and bumps Dropped() instead
+// of stalling.
+func (s *Source) callback(in []int16) {
+	s.ring.Write(in)
+}
+
+// Read returns little-endian PCM bytes pulled from the ring buffer. It
+// returns as many complete samples as are currently available and never
+// waits for more: when the ring buffer is empty it returns 0, nil.
+func (s *Source) Read(p []byte) (int, error) {
+	if atomic.LoadInt32(&s.closed) != 0 {
+		return 0, ErrClosed
+	}
+
+	samples := make([]int16, len(p)/2)
+	n := s.ring.Read(samples)
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint16(p[i*2:], uint16(samples[i]))
+	}
+	return n * 2, nil
+}
+
+// Dropped returns the number of samples silently dropped because the
+// ring buffer was full when the PortAudio callback tried to write them.
+// A non-zero count means Read is falling behind the audio thread.
+func (s *Source) Dropped() uint64 {
+	return s.ring.Dropped()
+}
+
+// Close stops and terminates the PortAudio stream. Calls after the first
+// do nothing and return nil; subsequent Reads return ErrClosed.
+func (s *Source) Close() error {
+	if !atomic.CompareAndSwapInt32(&s.closed, 0, 1) {
+		return nil
+	}
+	err := s.stream.Close()
+	if tErr := portaudio.Terminate(); err == nil {
+		err = tErr
+	}
+	return err
+}
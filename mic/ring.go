@@ -0,0 +1,66 @@
+package mic
+
+import "sync/atomic"
+
+// ringBuffer is a lock-free single-producer/single-consumer circular
+// buffer of int16 samples. The PortAudio callback is the sole writer and
+// Source.Read is the sole reader, so head/tail can be coordinated with
+// plain atomics instead of a mutex.
+type ringBuffer struct {
+	buf     []int16
+	head    uint64 // next write index, producer-owned
+	tail    uint64 // next read index, consumer-owned
+	dropped uint64
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	if size <= 0 {
+		size = 1
+	}
+	return &ringBuffer{buf: make([]int16, size)}
+}
+
+// Write copies as many samples from in as fit without overwriting
+// unread data. Samples that don't fit are dropped and counted rather
+// than blocking the caller.
+func (r *ringBuffer) Write(in []int16) {
+	head := atomic.LoadUint64(&r.head)
+	tail := atomic.LoadUint64(&r.tail)
+	free := uint64(len(r.buf)) - (head - tail)
+
+	n := uint64(len(in))
+	if n > free {
+		atomic.AddUint64(&r.dropped, n-free)
+		n = free
+	}
+
+	for i := uint64(0); i < n; i++ {
+		r.buf[(head+i)%uint64(len(r.buf))] = in[i]
+	}
+	atomic.StoreUint64(&r.head, head+n)
+}
+
+// Read copies up to len(out) available samples into out and returns how
+// many were copied.
+func (r *ringBuffer) Read(out []int16) int {
+	head := atomic.LoadUint64(&r.head)
+	tail := atomic.LoadUint64(&r.tail)
+	available := head - tail
+
+	n := uint64(len(out))
+	if n > available {
+		n = available
+	}
+
+	for i := uint64(0); i < n; i++ {
+		out[i] = r.buf[(tail+i)%uint64(len(r.buf))]
+	}
+	atomic.StoreUint64(&r.tail, tail+n)
+	return int(n)
+}
+
+// Dropped returns the running count of samples dropped because the
+// buffer was full when the producer tried to write.
+func (r *ringBuffer) Dropped() uint64 {
+	return atomic.LoadUint64(&r.dropped)
+}
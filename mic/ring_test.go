@@ -0,0 +1,90 @@
+package mic
+
+import "testing"
+
+func TestRingBufferWriteRead(t *testing.T) {
+	r := newRingBuffer(4)
+	r.Write([]int16{1, 2, 3})
+
+	out := make([]int16, 4)
+	n := r.Read(out)
+	if n != 3 {
+		t.Fatalf("Read() = %d, want 3", n)
+	}
+	want := []int16{1, 2, 3}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %d, want %d", i, out[i], v)
+		}
+	}
+}
+
+func TestRingBufferWrapAround(t *testing.T) {
+	r := newRingBuffer(4)
+	r.Write([]int16{1, 2, 3})
+
+	out := make([]int16, 2)
+	if n := r.Read(out); n != 2 {
+		t.Fatalf("Read() = %d, want 2", n)
+	}
+
+	// head=3, tail=2: three free slots, one before the end of buf and
+	// two after head wraps to index 0.
+	r.Write([]int16{4, 5, 6})
+
+	rest := make([]int16, 4)
+	n := r.Read(rest)
+	if n != 4 {
+		t.Fatalf("Read() = %d, want 4", n)
+	}
+	want := []int16{3, 4, 5, 6}
+	for i, v := range want {
+		if rest[i] != v {
+			t.Errorf("rest[%d] = %d, want %d", i, rest[i], v)
+		}
+	}
+}
+
+func TestRingBufferDropsWhenFull(t *testing.T) {
+	r := newRingBuffer(4)
+	r.Write([]int16{1, 2, 3, 4})
+	r.Write([]int16{5, 6}) // buffer is full; both samples should be dropped
+
+	if got := r.Dropped(); got != 2 {
+		t.Fatalf("Dropped() = %d, want 2", got)
+	}
+
+	out := make([]int16, 4)
+	n := r.Read(out)
+	if n != 4 {
+		t.Fatalf("Read() = %d, want 4", n)
+	}
+	want := []int16{1, 2, 3, 4}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %d, want %d", i, out[i], v)
+		}
+	}
+}
+
+func TestRingBufferPartialDrop(t *testing.T) {
+	r := newRingBuffer(4)
+	r.Write([]int16{1, 2})
+	r.Write([]int16{3, 4, 5, 6}) // only 2 free slots; last 2 samples dropped
+
+	if got := r.Dropped(); got != 2 {
+		t.Fatalf("Dropped() = %d, want 2", got)
+	}
+
+	out := make([]int16, 4)
+	n := r.Read(out)
+	if n != 4 {
+		t.Fatalf("Read() = %d, want 4", n)
+	}
+	want := []int16{1, 2, 3, 4}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %d, want %d", i, out[i], v)
+		}
+	}
+}
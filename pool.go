@@ -0,0 +1,204 @@
+package snowboy
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrPoolClosed is returned by Pool methods once Close has been called.
+var ErrPoolClosed = errors.New("snowboy: pool closed")
+
+// Pool vends Detector instances that share a common resource file,
+// hotwords and audio configuration. Detectors returned to the pool via
+// Release are reused by later Acquire calls instead of being torn down,
+// so the underlying SnowboyDetect (and its model load cost) is amortized
+// across sessions rather than paid per-stream.
+type Pool struct {
+	mu            sync.Mutex
+	resourceFile  string
+	audioGain     float32
+	applyFrontend bool
+	hotwords      []Hotword
+
+	free   []*Detector
+	all    []*Detector // every Detector ever handed out by Acquire, on loan or not
+	closed bool
+}
+
+// NewPool creates a Pool that builds Detectors against resourceFile,
+// listening for each of hotwords. AudioGain defaults to 1.0, matching
+// NewDetector.
+func NewPool(resourceFile string, hotwords ...Hotword) *Pool {
+	return &Pool{
+		resourceFile: resourceFile,
+		audioGain:    1.0,
+		hotwords:     hotwords,
+	}
+}
+
+// SetAudioGain sets the gain applied to Detectors acquired after this
+// call. It does not affect Detectors already acquired.
+func (p *Pool) SetAudioGain(g float32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.audioGain = g
+}
+
+// SetApplyFrontend sets ApplyFrontend on Detectors acquired after this
+// call. It does not affect Detectors already acquired.
+func (p *Pool) SetApplyFrontend(apply bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.applyFrontend = apply
+}
+
+// Acquire returns a Detector configured with the pool's resource file
+// and hotwords, reusing one returned via Release when available.
+// Callers consume detections through the Detector's Events() channel.
+// The Detector is already initialized with a no-op Handler for each of
+// the pool's hotwords, so Handlers installed later with Handle are not
+// called for those hotwords.
+func (p *Pool) Acquire() (*Detector, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	if n := len(p.free); n > 0 {
+		d := p.free[n-1]
+		p.free = p.free[:n-1]
+		d.SetAudioGain(p.audioGain)
+		d.setApplyFrontend(p.applyFrontend)
+		return d, nil
+	}
+
+	d := NewDetector(p.resourceFile)
+	d.AudioGain = p.audioGain
+	d.ApplyFrontend = p.applyFrontend
+	for _, hw := range p.hotwords {
+		d.HandleFunc(hw, func(string) {})
+	}
+	d.initialize()
+	p.all = append(p.all, &d)
+	return &d, nil
+}
+
+// Release returns d to the pool for reuse, resetting its per-session
+// state. It must not be used again by the caller afterwards.
+func (p *Pool) Release(d *Detector) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if d.eventCh != nil {
+		close(d.eventCh)
+		d.eventCh = nil
+	}
+	d.silenceElapsed = 0
+
+	if p.closed {
+		d.Close()
+		return
+	}
+	p.free = append(p.free, d)
+}
+
+// StreamEvent pairs an Event with the ID of the stream that produced
+// it. The last StreamEvent sent for a given StreamID carries Err: the
+// error ReadAndDetect returned when that stream stopped (nil on a
+// clean EOF with no trailing route error). Event is zero-valued on
+// that final, Err-carrying StreamEvent.
+type StreamEvent struct {
+	StreamID string
+	Event
+	Err error
+}
+
+// DetectAll acquires one Detector per entry in streams, runs
+// ReadAndDetect on each in its own goroutine, and multiplexes their
+// Events() into a single channel keyed by stream ID. The returned
+// channel is closed once every stream has finished (EOF or error) and
+// its Detector has been Released back to the pool.
+//
+// Each stream's terminal error (a reader error, a Close-triggered
+// error, or a route error such as NoHandler) is reported on the
+// channel as the final StreamEvent for that StreamID, with Err set,
+// rather than discarded; a clean exit still sends a final StreamEvent
+// with Err == nil so callers can detect completion per stream.
+//
+// All Detectors are acquired up front, before any stream starts
+// reading. If acquiring one fails partway through (e.g. the pool is
+// Closed concurrently), the Detectors already acquired for this call
+// are Released again and the error is returned without starting any
+// goroutines, so no detections are ever produced on a channel the
+// caller never received.
+func (p *Pool) DetectAll(streams map[string]io.Reader) (<-chan StreamEvent, error) {
+	type session struct {
+		id string
+		r  io.Reader
+		d  *Detector
+	}
+
+	sessions := make([]session, 0, len(streams))
+	for id, r := range streams {
+		d, err := p.Acquire()
+		if err != nil {
+			for _, s := range sessions {
+				p.Release(s.d)
+			}
+			return nil, err
+		}
+		sessions = append(sessions, session{id: id, r: r, d: d})
+	}
+
+	out := make(chan StreamEvent, 16*len(sessions))
+
+	var wg sync.WaitGroup
+	for _, s := range sessions {
+		events := s.d.Events()
+
+		wg.Add(1)
+		go func(id string, d *Detector, r io.Reader, events <-chan Event) {
+			defer wg.Done()
+
+			pumped := make(chan struct{})
+			go func() {
+				defer close(pumped)
+				for event := range events {
+					out <- StreamEvent{StreamID: id, Event: event}
+				}
+			}()
+
+			err := d.ReadAndDetect(r)
+			p.Release(d)
+			<-pumped
+			out <- StreamEvent{StreamID: id, Err: err}
+		}(s.id, s.d, s.r, events)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// Close releases every Detector ever handed out by the pool, whether
+// idle in the free list or still on loan to a caller, and tears down
+// its underlying SnowboyDetect object. Acquire returns ErrPoolClosed
+// after Close.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for _, d := range p.all {
+		d.Close()
+	}
+	p.free = nil
+	p.all = nil
+	return nil
+}
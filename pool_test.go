@@ -0,0 +1,52 @@
+package snowboy
+
+import "testing"
+
+func TestPoolAcquireAfterCloseReturnsErrPoolClosed(t *testing.T) {
+	p := NewPool("resource.pmdl")
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := p.Acquire(); err != ErrPoolClosed {
+		t.Fatalf("Acquire() after Close() error = %v, want %v", err, ErrPoolClosed)
+	}
+}
+
+func TestPoolReleaseAddsToFreeList(t *testing.T) {
+	p := NewPool("resource.pmdl")
+	d := &Detector{}
+
+	p.Release(d)
+
+	if len(p.free) != 1 || p.free[0] != d {
+		t.Fatalf("Release() did not add d to the free list: %v", p.free)
+	}
+}
+
+func TestPoolReleaseAfterCloseDoesNotReuse(t *testing.T) {
+	p := NewPool("resource.pmdl")
+	d := &Detector{}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	p.Release(d)
+
+	if len(p.free) != 0 {
+		t.Fatalf("Release() after Close() should not grow the free list, got %v", p.free)
+	}
+}
+
+func TestPoolCloseDrainsFreeAndAllLists(t *testing.T) {
+	p := NewPool("resource.pmdl")
+	p.free = []*Detector{{}, {}}
+	p.all = []*Detector{p.free[0], p.free[1]}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if p.free != nil || p.all != nil {
+		t.Fatalf("Close() should clear free and all, got free=%v all=%v", p.free, p.all)
+	}
+}
@@ -0,0 +1,73 @@
+// Package promexport implements snowboy.Metrics with Prometheus
+// counters and histograms, so callers get detection/silence/latency
+// visibility without the core snowboy package taking a hard dependency
+// on prometheus/client_golang.
+package promexport
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements snowboy.Metrics. Register it with a
+// prometheus.Registerer (or use the default one via NewMetrics) and
+// assign it to Detector.Metrics.
+type Metrics struct {
+	detections     *prometheus.CounterVec
+	silenceSeconds prometheus.Histogram
+	detectLatency  prometheus.Histogram
+	errors         prometheus.Counter
+}
+
+// NewMetrics creates a Metrics and registers its collectors with reg.
+// Pass prometheus.DefaultRegisterer to use the global registry.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		detections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "snowboy",
+			Name:      "detections_total",
+			Help:      "Number of hotword detections, labeled by keyword.",
+		}, []string{"keyword"}),
+		silenceSeconds: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "snowboy",
+			Name:      "silence_seconds",
+			Help:      "Duration of silence accumulated between hotword detections.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+		}),
+		detectLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "snowboy",
+			Name:      "detect_latency_seconds",
+			Help:      "Latency of a single RunDetection call over one chunk of audio.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "snowboy",
+			Name:      "errors_total",
+			Help:      "Number of errors reported by the underlying snowboy detector.",
+		}),
+	}
+
+	reg.MustRegister(m.detections, m.silenceSeconds, m.detectLatency, m.errors)
+	return m
+}
+
+// ObserveDetection implements snowboy.Metrics.
+func (m *Metrics) ObserveDetection(keyword string) {
+	m.detections.WithLabelValues(keyword).Inc()
+}
+
+// ObserveSilence implements snowboy.Metrics.
+func (m *Metrics) ObserveSilence(d time.Duration) {
+	m.silenceSeconds.Observe(d.Seconds())
+}
+
+// ObserveDetectLatency implements snowboy.Metrics.
+func (m *Metrics) ObserveDetectLatency(d time.Duration) {
+	m.detectLatency.Observe(d.Seconds())
+}
+
+// ObserveError implements snowboy.Metrics.
+func (m *Metrics) ObserveError(err error) {
+	m.errors.Inc()
+}
@@ -1,13 +1,16 @@
 package snowboy
 
 import (
+	"context"
 	"errors"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
+	"github.com/H4xorPL/go-snowboy/audio"
 	"github.com/Kitt-AI/snowboy/swig/Go"
 )
 
@@ -29,6 +32,14 @@ type Detector struct {
 	silenceElapsed   time.Duration
 	ResourceFile     string
 	AudioGain        float32
+	ApplyFrontend    bool
+	mu               sync.Mutex
+	closeOnce        sync.Once
+	closeCh          chan struct{}
+	EventBufferSize  int
+	EventDropOldest  bool
+	eventCh          chan Event
+	Metrics          Metrics
 }
 
 // Creates a standard Detector from a resources file
@@ -36,7 +47,8 @@ type Detector struct {
 func NewDetector(resourceFile string) Detector {
 	return Detector{
 		ResourceFile: resourceFile,
-		AudioGain: 1.0,
+		AudioGain:    1.0,
+		closeCh:      make(chan struct{}),
 	}
 }
 
@@ -44,7 +56,26 @@ func NewDetector(resourceFile string) Detector {
 //
 // Clients must call Close on detectors after doing any detection
 // Returns error if Detector was never used
+//
+// Close is safe to call from a goroutine other than the one running
+// ReadAndDetect/ReadAndDetectContext, even before that goroutine has
+// made its first call: closeCh is created once, in NewDetector, so
+// there is no window where closeOnce could fire against a not-yet-
+// created channel and silently skip the close. Close also takes d.mu
+// before touching closeCh, the same mutex initialize() and runDetection
+// hold, so it can never observe state partway through an initialize()
+// call on a Detector built some other way than NewDetector; it then
+// waits for any detection already in flight to finish before tearing
+// down the underlying detector, so it never races a concurrent
+// runDetection call.
 func (d *Detector) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closeOnce.Do(func() {
+		if d.closeCh != nil {
+			close(d.closeCh)
+		}
+	})
 	if d.initialized {
 		d.initialized = false
 		snowboydetect.DeleteSnowboyDetect(d.raw)
@@ -96,10 +127,34 @@ func (d *Detector) HandleSilenceFunc(threshold time.Duration, handler func(strin
 }
 
 // Reads from data and calls previously installed handlers when detection occurs
+//
+// ReadAndDetect runs until data is exhausted, an error occurs, or the
+// Detector is Closed; it takes no context to cancel. For long-running
+// services, use ReadAndDetectContext instead.
 func (d *Detector) ReadAndDetect(data io.Reader) error {
+	return d.ReadAndDetectContext(context.Background(), data)
+}
+
+// ReadAndDetectContext is ReadAndDetect with cancellation: it checks
+// ctx.Done() between reads and returns ctx.Err() promptly once ctx is
+// cancelled, instead of sleeping through a fixed 300ms backoff. Close
+// stops the loop the same way, between reads or during the wait after
+// an empty read. A data.Read call that is already blocked is not
+// interrupted by either.
+func (d *Detector) ReadAndDetectContext(ctx context.Context, data io.Reader) error {
 	d.initialize()
 	bytes := make([]byte, 2048)
+	timer := time.NewTimer(300 * time.Millisecond)
+	defer timer.Stop()
 	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.closeCh:
+			return io.ErrClosedPipe
+		default:
+		}
+
 		n, err := data.Read(bytes)
 		if err != nil {
 			if err == io.EOF {
@@ -110,7 +165,20 @@ func (d *Detector) ReadAndDetect(data io.Reader) error {
 		}
 		if n == 0 {
 			// No data to read yet, but not eof so wait and try later
-			time.Sleep(300 * time.Millisecond)
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(300 * time.Millisecond)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-d.closeCh:
+				return io.ErrClosedPipe
+			case <-timer.C:
+			}
 			continue
 		}
 		err = d.route(d.runDetection(bytes))
@@ -120,8 +188,39 @@ func (d *Detector) ReadAndDetect(data io.Reader) error {
 	}
 }
 
+// DetectSource runs detection over src, an audio.Source, until it
+// returns io.EOF. Unlike ReadAndDetect, src is expected to already
+// yield int16 samples at the Detector's native AudioFormat; use the
+// adapters in the audio subpackage (WAV stripping, int32/float32
+// conversion, resampling) to get there from whatever src actually
+// produces.
+func (d *Detector) DetectSource(src audio.Source) error {
+	d.initialize()
+	samples := make([]int16, 1024)
+	for {
+		n, err := src.Read(samples)
+		if n > 0 {
+			if routeErr := d.route(d.runDetectionSamples(samples[:n])); routeErr != nil {
+				return routeErr
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
 func (d *Detector) AudioFormat() (sampleRate, numChannels, bitsPerSample int) {
 	d.initialize()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.initialized {
+		// Closed concurrently between initialize() and here.
+		return
+	}
 	sampleRate = d.raw.SampleRate()
 	numChannels = d.raw.NumChannels()
 	bitsPerSample = d.raw.BitsPerSample()
@@ -129,17 +228,93 @@ func (d *Detector) AudioFormat() (sampleRate, numChannels, bitsPerSample int) {
 }
 
 func (d *Detector) initialize() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	if d.initialized {
 		return
 	}
+	if d.closeCh == nil {
+		d.closeCh = make(chan struct{})
+	}
 	d.raw = snowboydetect.NewSnowboyDetect(d.ResourceFile, d.modelStr)
 	d.raw.SetSensitivity(d.sensitivityStr)
 	d.raw.SetAudioGain(d.AudioGain)
+	d.raw.ApplyFrontend(d.ApplyFrontend)
 	d.initialized = true
 }
 
+// SetSensitivity updates the detection sensitivity for hotword on an
+// already-initialized Detector, without tearing down and rebuilding it.
+//
+// hotword must match the Name of a Hotword previously passed to Handle.
+func (d *Detector) SetSensitivity(hotword string, s float32) {
+	d.initialize()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.initialized {
+		// Closed concurrently between initialize() and here.
+		return
+	}
+	sensitivities := strings.Split(d.sensitivityStr, ",")
+	for result, hk := range d.handlers {
+		if result == snowboyResultSilence || hk.keyword != hotword {
+			continue
+		}
+		if idx := int(result) - 1; idx >= 0 && idx < len(sensitivities) {
+			sensitivities[idx] = strconv.FormatFloat(float64(s), 'f', 2, 64)
+		}
+	}
+	d.sensitivityStr = strings.Join(sensitivities, ",")
+	d.raw.SetSensitivity(d.sensitivityStr)
+}
+
+// SetAudioGain updates the input gain on an already-initialized Detector.
+func (d *Detector) SetAudioGain(g float32) {
+	d.initialize()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.AudioGain = g
+	if !d.initialized {
+		// Closed concurrently between initialize() and here.
+		return
+	}
+	d.raw.SetAudioGain(g)
+}
+
+// setApplyFrontend updates ApplyFrontend on an already-initialized
+// Detector. Unexported: Pool is the only caller that needs to
+// reconfigure the frontend on a Detector it has already built.
+func (d *Detector) setApplyFrontend(apply bool) {
+	d.initialize()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.ApplyFrontend = apply
+	if !d.initialized {
+		// Closed concurrently between initialize() and here.
+		return
+	}
+	d.raw.ApplyFrontend(apply)
+}
+
+// NumHotwords returns the number of hotwords currently installed on the
+// underlying detector.
+func (d *Detector) NumHotwords() int {
+	d.initialize()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.initialized {
+		// Closed concurrently between initialize() and here.
+		return 0
+	}
+	return d.raw.NumHotwords()
+}
+
 func (d *Detector) route(result snowboyResult) error {
 	if result == snowboyResultError {
+		d.publish(EventError, "")
+		if d.Metrics != nil {
+			d.Metrics.ObserveError(SnowboyLibraryError)
+		}
 		return SnowboyLibraryError
 	} else if result != snowboyResultNoDetection {
 		handlerKeyword, ok := d.handlers[result]
@@ -148,8 +323,20 @@ func (d *Detector) route(result snowboyResult) error {
 				// Skip silence callback because threshold has not be surpassed
 				return nil
 			}
+			if d.Metrics != nil {
+				if result == snowboyResultSilence {
+					d.Metrics.ObserveSilence(d.silenceElapsed)
+				} else {
+					d.Metrics.ObserveDetection(handlerKeyword.keyword)
+				}
+			}
 			// Reset silence elapse because it's got called
 			d.silenceElapsed = 0
+			kind := EventHotword
+			if result == snowboyResultSilence {
+				kind = EventSilence
+			}
+			d.publish(kind, handlerKeyword.keyword)
 			handlerKeyword.call()
 		} else {
 			return NoHandler
@@ -158,21 +345,145 @@ func (d *Detector) route(result snowboyResult) error {
 	return nil
 }
 
+// Metrics receives optional instrumentation callbacks from route() and
+// runDetection. A nil Metrics (the default) disables all instrumentation
+// callbacks; the core package has no hard
+// dependency on any particular metrics backend. See the promexport
+// subpackage for a Prometheus-backed implementation.
+type Metrics interface {
+	ObserveDetection(keyword string)
+	ObserveSilence(d time.Duration)
+	ObserveDetectLatency(d time.Duration)
+	ObserveError(err error)
+}
+
+// EventKind identifies what kind of Event was published on
+// Detector.Events().
+type EventKind int
+
+const (
+	// EventHotword fires when a hotword's handler would be called.
+	EventHotword EventKind = iota
+	// EventSilence fires when the silence handler would be called.
+	EventSilence
+	// EventError fires when RunDetection reports a library error.
+	EventError
+)
+
+// Event is a single detection event published on Detector.Events() as
+// an alternative to installing Handler callbacks.
+type Event struct {
+	Keyword   string
+	Kind      EventKind
+	Timestamp time.Time
+}
+
+// EventBufferSize and EventDropOldest configure the channel returned by
+// Events; see Events for their effect. They must be set before the
+// first call to Events.
+//
+// Events returns a channel of detection events, so that consumers can
+// select on detections alongside their own timers/contexts instead of
+// running handler callbacks on the audio-reading goroutine. The channel
+// is created on first call, buffered to EventBufferSize (default 16);
+// subsequent calls return the same channel.
+//
+// When EventDropOldest is true, a full channel drops its oldest queued
+// event to make room rather than blocking route() on a slow consumer.
+func (d *Detector) Events() <-chan Event {
+	if d.eventCh == nil {
+		size := d.EventBufferSize
+		if size <= 0 {
+			size = 16
+		}
+		d.eventCh = make(chan Event, size)
+	}
+	return d.eventCh
+}
+
+func (d *Detector) publish(kind EventKind, keyword string) {
+	if d.eventCh == nil {
+		return
+	}
+	event := Event{Keyword: keyword, Kind: kind, Timestamp: time.Now()}
+	if !d.EventDropOldest {
+		d.eventCh <- event
+		return
+	}
+	select {
+	case d.eventCh <- event:
+	default:
+		select {
+		case <-d.eventCh:
+		default:
+		}
+		select {
+		case d.eventCh <- event:
+		default:
+		}
+	}
+}
+
+// runDetection holds d.mu for the whole RunDetection call (and the
+// silence bookkeeping that follows it) so that a concurrent Close
+// cannot delete the underlying detector out from under an in-flight
+// call: Close blocks on the same mutex until this returns.
 func (d *Detector) runDetection(data []byte) snowboyResult {
 	if len(data) == 0 {
 		return 0
 	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.initialized {
+		// Closed concurrently; nothing left to detect against.
+		return 0
+	}
 	ptr := snowboydetect.SwigcptrInt16_t(unsafe.Pointer(&data[0]))
-	result := snowboyResult(d.raw.RunDetection(ptr, len(data) / 2 /* len of int16 */))
+	start := time.Now()
+	result := snowboyResult(d.raw.RunDetection(ptr, len(data)/2 /* len of int16 */))
+	if d.Metrics != nil {
+		d.Metrics.ObserveDetectLatency(time.Since(start))
+	}
+	d.recordSilenceLocked(result, len(data)/2)
+	return result
+}
+
+// runDetectionSamples is runDetection for callers that already have
+// int16 samples in hand (e.g. DetectSource), skipping the byte<->int16
+// pointer arithmetic runDetection needs for a raw io.Reader.
+func (d *Detector) runDetectionSamples(samples []int16) snowboyResult {
+	if len(samples) == 0 {
+		return 0
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.initialized {
+		// Closed concurrently; nothing left to detect against.
+		return 0
+	}
+	ptr := snowboydetect.SwigcptrInt16_t(unsafe.Pointer(&samples[0]))
+	start := time.Now()
+	result := snowboyResult(d.raw.RunDetection(ptr, len(samples)))
+	if d.Metrics != nil {
+		d.Metrics.ObserveDetectLatency(time.Since(start))
+	}
+	d.recordSilenceLocked(result, len(samples))
+	return result
+}
+
+// recordSilenceLocked updates silenceElapsed given the outcome of a
+// RunDetection call over numSamples samples. Callers must already hold
+// d.mu and have confirmed d.initialized.
+func (d *Detector) recordSilenceLocked(result snowboyResult, numSamples int) {
 	if result == snowboyResultSilence {
-		sampleRate, numChannels, bitDepth := d.AudioFormat()
-		dataElapseTime := len(data) * int(time.Second) / (numChannels * (bitDepth / 8) * sampleRate)
+		sampleRate := d.raw.SampleRate()
+		numChannels := d.raw.NumChannels()
+		dataElapseTime := numSamples * int(time.Second) / (numChannels * sampleRate)
 		d.silenceElapsed += time.Duration(dataElapseTime)
 	} else {
 		// Reset silence elapse duration because non-silence was detected
 		d.silenceElapsed = 0
 	}
-	return result
 }
 
 var NoHandler = errors.New("No handler installed")